@@ -0,0 +1,166 @@
+package operations
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/mongodb/grip"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli"
+)
+
+const (
+	sshConfigBeginMarker = "# ---BEGIN EVERGREEN---"
+	sshConfigEndMarker   = "# ---END EVERGREEN---"
+)
+
+// hostConfigSSH returns the "config-ssh" subcommand, which belongs in the
+// "host" command's Subcommands list alongside list/spawn/stop/etc.
+func hostConfigSSH() cli.Command {
+	const (
+		dryRunFlagName        = "dry-run"
+		sshConfigFileFlagName = "ssh-config-file"
+	)
+
+	return cli.Command{
+		Name:  "config-ssh",
+		Usage: "write an OpenSSH config block for your Evergreen spawn hosts",
+		Flags: addPathFlag(
+			cli.BoolFlag{
+				Name:  dryRunFlagName,
+				Usage: "print the generated config block instead of writing it",
+			},
+			cli.StringFlag{
+				Name:  sshConfigFileFlagName,
+				Usage: "path to the SSH config file to update",
+				Value: defaultSSHConfigPath(),
+			},
+		),
+		Before: mergeBeforeFuncs(setPlainLogger, requireClientConfig),
+		Action: func(c *cli.Context) error {
+			confPath := c.Parent().Parent().String(confFlagName)
+			dryRun := c.Bool(dryRunFlagName)
+			sshConfigFile := c.String(sshConfigFileFlagName)
+
+			ctx, cancel := getContextWithTimeout()
+			defer cancel()
+
+			conf, err := NewClientSettings(confPath)
+			if err != nil {
+				return errors.Wrap(err, "error loading configuration")
+			}
+			comm, err := conf.setupRestCommunicator(ctx, true)
+			if err != nil {
+				return err
+			}
+			defer comm.Close()
+
+			hosts, err := comm.GetHostsByUser(ctx, conf.User)
+			if err != nil {
+				return errors.Wrap(err, "error fetching spawn hosts")
+			}
+
+			block, err := buildSSHConfigBlock(hosts)
+			if err != nil {
+				return errors.Wrap(err, "error building SSH config block")
+			}
+
+			if dryRun {
+				fmt.Println(block)
+				return nil
+			}
+
+			return writeSSHConfigBlock(sshConfigFile, block)
+		},
+	}
+}
+
+// buildSSHConfigBlock renders one "Host evg-<hostid>" stanza per host,
+// reusing the same options the server-side provisioner uses to reach the
+// host over SSH.
+func buildSSHConfigBlock(hosts []*model.APIHost) (string, error) {
+	buf := &bytes.Buffer{}
+	fmt.Fprintln(buf, sshConfigBeginMarker)
+	for _, h := range hosts {
+		sshInfo, err := h.GetSSHInfo()
+		if err != nil {
+			grip.Warning(errors.Wrapf(err, "skipping host %s with unparsable SSH info", h.Id))
+			continue
+		}
+		sshOptions, err := h.GetSSHOptions()
+		if err != nil {
+			grip.Warning(errors.Wrapf(err, "skipping host %s with unavailable SSH options", h.Id))
+			continue
+		}
+
+		fmt.Fprintf(buf, "Host evg-%s\n", h.Id)
+		fmt.Fprintf(buf, "    HostName %s\n", sshInfo.Hostname)
+		fmt.Fprintf(buf, "    User %s\n", h.User)
+		fmt.Fprintf(buf, "    Port %s\n", sshInfo.Port)
+		fmt.Fprintf(buf, "    IdentityFile %s\n", h.IdentityFile)
+		for _, opt := range sshOptions {
+			fmt.Fprintf(buf, "    %s\n", opt)
+		}
+		fmt.Fprintln(buf)
+	}
+	fmt.Fprintln(buf, sshConfigEndMarker)
+
+	return buf.String(), nil
+}
+
+// writeSSHConfigBlock replaces the managed Evergreen section of sshConfigFile
+// with block, preserving everything else in the file. The managed section is
+// created if it doesn't already exist.
+func writeSSHConfigBlock(sshConfigFile, block string) error {
+	existing, err := ioutil.ReadFile(sshConfigFile)
+	if err != nil && !os.IsNotExist(err) {
+		return errors.Wrapf(err, "error reading %s", sshConfigFile)
+	}
+
+	var out bytes.Buffer
+	wroteBlock := false
+	inManagedSection := false
+	scanner := bufio.NewScanner(bytes.NewReader(existing))
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.TrimSpace(line) == sshConfigBeginMarker:
+			inManagedSection = true
+			out.WriteString(block)
+			wroteBlock = true
+		case strings.TrimSpace(line) == sshConfigEndMarker:
+			inManagedSection = false
+		case inManagedSection:
+			// skip, this is replaced by block
+		default:
+			out.WriteString(line)
+			out.WriteString("\n")
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return errors.Wrapf(err, "error reading %s", sshConfigFile)
+	}
+	if !wroteBlock {
+		out.WriteString(block)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(sshConfigFile), 0700); err != nil {
+		return errors.Wrapf(err, "error creating directory for %s", sshConfigFile)
+	}
+
+	return errors.Wrapf(ioutil.WriteFile(sshConfigFile, out.Bytes(), 0600), "error writing %s", sshConfigFile)
+}
+
+func defaultSSHConfigPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".ssh", "config")
+}
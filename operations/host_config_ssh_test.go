@@ -0,0 +1,74 @@
+package operations
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteSSHConfigBlockCreatesFreshFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ssh-config-test")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	sshConfigFile := filepath.Join(dir, "nested", "config")
+	block := sshConfigBeginMarker + "\nHost evg-1\n" + sshConfigEndMarker + "\n"
+
+	require.NoError(t, writeSSHConfigBlock(sshConfigFile, block))
+
+	contents, err := ioutil.ReadFile(sshConfigFile)
+	require.NoError(t, err)
+	assert.Equal(t, block, string(contents))
+}
+
+func TestWriteSSHConfigBlockRefreshesInPlaceAndKeepsSurroundingContent(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ssh-config-test")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	sshConfigFile := filepath.Join(dir, "config")
+	existing := "Host myserver\n    HostName example.com\n\n" +
+		sshConfigBeginMarker + "\nHost evg-1\n" + sshConfigEndMarker + "\n\n" +
+		"Host otherserver\n    User bob\n"
+	require.NoError(t, ioutil.WriteFile(sshConfigFile, []byte(existing), 0600))
+
+	newBlock := sshConfigBeginMarker + "\nHost evg-2\n" + sshConfigEndMarker + "\n"
+	require.NoError(t, writeSSHConfigBlock(sshConfigFile, newBlock))
+
+	contents, err := ioutil.ReadFile(sshConfigFile)
+	require.NoError(t, err)
+
+	updated := string(contents)
+	assert.Contains(t, updated, "Host myserver")
+	assert.Contains(t, updated, "Host otherserver")
+	assert.Contains(t, updated, "Host evg-2")
+	assert.NotContains(t, updated, "Host evg-1")
+}
+
+func TestWriteSSHConfigBlockToleratesIndentedMarkers(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ssh-config-test")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	sshConfigFile := filepath.Join(dir, "config")
+	existing := "  " + sshConfigBeginMarker + "  \nHost evg-1\n  " + sshConfigEndMarker + "\n"
+	require.NoError(t, ioutil.WriteFile(sshConfigFile, []byte(existing), 0600))
+
+	newBlock := sshConfigBeginMarker + "\nHost evg-2\n" + sshConfigEndMarker + "\n"
+	require.NoError(t, writeSSHConfigBlock(sshConfigFile, newBlock))
+
+	contents, err := ioutil.ReadFile(sshConfigFile)
+	require.NoError(t, err)
+
+	// The managed section should be replaced in place, not appended a
+	// second time.
+	updated := string(contents)
+	assert.Equal(t, 1, strings.Count(updated, sshConfigBeginMarker))
+	assert.Contains(t, updated, "Host evg-2")
+	assert.NotContains(t, updated, "Host evg-1")
+}
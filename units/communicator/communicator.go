@@ -0,0 +1,132 @@
+// Package communicator abstracts the remote commands that setupHostJob needs
+// to run against a provisioned host behind a single interface, so that
+// callers don't have to hard-code POSIX shell semantics (mkdir -m, ~/.profile,
+// scp) that don't apply to Windows hosts bootstrapped over WinRM.
+package communicator
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model/distro"
+	"github.com/evergreen-ci/evergreen/model/host"
+	"github.com/pkg/errors"
+)
+
+// Communicator runs commands and transfers files to a provisioned host
+// without the caller needing to know whether the host is reached over SSH or
+// WinRM.
+type Communicator interface {
+	// Run executes cmd on the host and returns its combined output.
+	Run(ctx context.Context, cmd string) (output string, err error)
+	// RunStreaming executes cmd on the host, writing its output to out as it
+	// is produced rather than buffering the whole thing in memory. It never
+	// allocates a pseudo-terminal, so it's suitable for commands that may run
+	// for a long time or produce a lot of output, such as fetching the agent
+	// or task data.
+	RunStreaming(ctx context.Context, cmd string, out io.Writer) error
+	// CopyFile writes content to path on the host with the given mode. The
+	// WinRM implementation ignores mode.
+	CopyFile(ctx context.Context, path string, mode int, content []byte) error
+	// MakeDir creates path (and any parents) on the host with the given mode.
+	// The WinRM implementation ignores mode.
+	MakeDir(ctx context.Context, path string, mode int) error
+	// AppendToShellProfile appends line to the host user's shell startup
+	// file(s). On WinRM hosts this is a no-op and nothing is written.
+	AppendToShellProfile(ctx context.Context, line string) error
+}
+
+// New returns the Communicator appropriate for h's configured communication
+// method.
+func New(env evergreen.Environment, h *host.Host, settings *evergreen.Settings) (Communicator, error) {
+	switch h.CommunicationMethod {
+	case distro.CommunicationMethodWinRM:
+		opts, err := h.GetWinRMOptions(settings)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error getting WinRM options for host %s", h.Id)
+		}
+		return &winrmCommunicator{host: h, options: opts}, nil
+	case distro.CommunicationMethodSSH, "":
+		opts, err := h.GetSSHOptions(settings)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error getting SSH options for host %s", h.Id)
+		}
+		return &sshCommunicator{env: env, host: h, options: opts}, nil
+	default:
+		return nil, errors.Errorf("unrecognized communication method '%s' for host %s", h.CommunicationMethod, h.Id)
+	}
+}
+
+type sshCommunicator struct {
+	env     evergreen.Environment
+	host    *host.Host
+	options []string
+}
+
+func (c *sshCommunicator) Run(ctx context.Context, cmd string) (string, error) {
+	output, err := c.host.RunSSHCommand(ctx, cmd, c.options)
+	return output, errors.WithStack(err)
+}
+
+// noPTYSSHOptions returns c.options with "-T" appended, which tells the SSH
+// client to disable pseudo-terminal allocation. Long-running setup commands
+// don't need a TTY, and allocating one makes the remote process die when the
+// SSH connection is interrupted rather than letting it finish in the
+// background.
+func (c *sshCommunicator) noPTYSSHOptions() []string {
+	return append(append([]string{}, c.options...), "-T")
+}
+
+func (c *sshCommunicator) RunStreaming(ctx context.Context, cmd string, out io.Writer) error {
+	err := c.host.RunSSHCommandWithWriter(ctx, cmd, c.noPTYSSHOptions(), out)
+	return errors.WithStack(err)
+}
+
+func (c *sshCommunicator) CopyFile(ctx context.Context, path string, mode int, content []byte) error {
+	return errors.WithStack(c.host.WriteRemoteFile(ctx, c.env, path, mode, content))
+}
+
+func (c *sshCommunicator) MakeDir(ctx context.Context, path string, mode int) error {
+	_, err := c.Run(ctx, fmt.Sprintf("mkdir -m %o -p %s", mode, path))
+	return errors.WithStack(err)
+}
+
+func (c *sshCommunicator) AppendToShellProfile(ctx context.Context, line string) error {
+	cmd := fmt.Sprintf("(echo '%s' >> ~/.profile || true; echo '%s' >> ~/.bash_profile || true)", line, line)
+	_, err := c.Run(ctx, cmd)
+	return errors.WithStack(err)
+}
+
+type winrmCommunicator struct {
+	host    *host.Host
+	options host.WinRMOptions
+}
+
+func (c *winrmCommunicator) Run(ctx context.Context, cmd string) (string, error) {
+	output, err := c.host.RunWinRMCommand(ctx, cmd, c.options)
+	return output, errors.WithStack(err)
+}
+
+// RunStreaming runs cmd over WinRM, writing output to out as it arrives.
+// WinRM shells never allocate a PTY, so there's nothing to disable here.
+func (c *winrmCommunicator) RunStreaming(ctx context.Context, cmd string, out io.Writer) error {
+	err := c.host.RunWinRMCommandWithWriter(ctx, cmd, c.options, out)
+	return errors.WithStack(err)
+}
+
+func (c *winrmCommunicator) CopyFile(ctx context.Context, path string, mode int, content []byte) error {
+	return errors.WithStack(c.host.CopyFileWinRM(ctx, path, content, c.options))
+}
+
+func (c *winrmCommunicator) MakeDir(ctx context.Context, path string, mode int) error {
+	_, err := c.Run(ctx, fmt.Sprintf("New-Item -ItemType Directory -Force -Path '%s'", path))
+	return errors.WithStack(err)
+}
+
+func (c *winrmCommunicator) AppendToShellProfile(ctx context.Context, line string) error {
+	// Windows hosts don't have a shell profile in the POSIX sense; $PATH
+	// updates are delivered as part of the agent's own bootstrap script.
+	return nil
+}
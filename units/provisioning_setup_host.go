@@ -7,6 +7,8 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"math"
+	"math/rand"
 	"os"
 	"path/filepath"
 	"strings"
@@ -18,6 +20,7 @@ import (
 	"github.com/evergreen-ci/evergreen/model/event"
 	"github.com/evergreen-ci/evergreen/model/host"
 	"github.com/evergreen-ci/evergreen/model/user"
+	"github.com/evergreen-ci/evergreen/units/communicator"
 	"github.com/evergreen-ci/evergreen/util"
 	"github.com/mongodb/amboy"
 	"github.com/mongodb/amboy/dependency"
@@ -32,6 +35,7 @@ const (
 	provisionRetryLimit = 15
 	setupHostJobName    = "provisioning-setup-host"
 	scpTimeout          = time.Minute
+	socketTimeout       = time.Minute
 )
 
 func init() {
@@ -123,6 +127,8 @@ func (j *setupHostJob) setupHost(ctx context.Context, h *host.Host, settings *ev
 		return errors.Wrapf(err, "hostinit canceled during setup for host %s", h.Id)
 	}
 
+	event.LogHostProvisionEvent(h.Id, event.ProvisionStarted, nil)
+
 	setupStartTime := time.Now()
 	grip.Info(message.Fields{
 		"message": "provisioning host",
@@ -133,6 +139,7 @@ func (j *setupHostJob) setupHost(ctx context.Context, h *host.Host, settings *ev
 
 	if err := j.provisionHost(ctx, h, settings); err != nil {
 		event.LogHostProvisionError(h.Id)
+		event.LogHostProvisionEvent(h.Id, event.ProvisionFailed, message.Fields{"reason": err.Error()})
 
 		if h.Distro.BootstrapSettings.Method == distro.BootstrapMethodSSH {
 			grip.Error(message.WrapError(j.host.DeleteJasperCredentials(ctx, j.env), message.Fields{
@@ -166,6 +173,7 @@ func (j *setupHostJob) setupHost(ctx context.Context, h *host.Host, settings *ev
 			"job":      j.ID(),
 			"message":  "retrying provisioning",
 		})
+		event.LogHostProvisionEvent(h.Id, event.ProvisionRetrying, message.Fields{"attempt": h.ProvisionAttempts})
 		return nil
 	}
 
@@ -178,6 +186,7 @@ func (j *setupHostJob) setupHost(ctx context.Context, h *host.Host, settings *ev
 		"attempts": h.ProvisionAttempts,
 		"runtime":  time.Since(setupStartTime),
 	})
+	event.LogHostProvisionEvent(h.Id, event.Provisioned, nil)
 
 	return nil
 }
@@ -284,6 +293,49 @@ func (j *setupHostJob) runHostSetup(ctx context.Context, targetHost *host.Host,
 			"job":     j.ID(),
 			"distro":  j.host.Distro.Id,
 		})
+		event.LogHostProvisionEvent(j.host.Id, event.JasperInstalled, nil)
+	case distro.BootstrapMethodSocket:
+		if err = setupJasperOverSocket(ctx, j.env, settings, j.host); err != nil {
+			grip.Error(message.WrapError(err, message.Fields{
+				"message": "could not set up Jasper over socket",
+				"host":    j.host.Id,
+				"distro":  j.host.Distro.Id,
+				"job":     j.ID(),
+			}))
+			return errors.Wrapf(err, "error putting Jasper on host '%s' over socket", targetHost.Id)
+		}
+		grip.Info(message.Fields{
+			"message": "successfully fetched Jasper binary and started service over socket",
+			"host":    j.host.Id,
+			"job":     j.ID(),
+			"distro":  j.host.Distro.Id,
+		})
+		event.LogHostProvisionEvent(j.host.Id, event.JasperInstalled, nil)
+	case distro.BootstrapMethodWinRM:
+		if err = setupJasperOverWinRM(ctx, j.env, settings, j.host); err != nil {
+			grip.Error(message.WrapError(err, message.Fields{
+				"message": "could not set up Jasper over WinRM",
+				"host":    j.host.Id,
+				"distro":  j.host.Distro.Id,
+				"job":     j.ID(),
+			}))
+			return errors.Wrapf(err, "error putting Jasper on host '%s' over WinRM", targetHost.Id)
+		}
+		grip.Info(message.Fields{
+			"message": "successfully fetched Jasper binary and started service over WinRM",
+			"host":    j.host.Id,
+			"job":     j.ID(),
+			"distro":  j.host.Distro.Id,
+		})
+		event.LogHostProvisionEvent(j.host.Id, event.JasperInstalled, nil)
+	}
+
+	// Only the methods above actually leave the app server able to reach the
+	// host (over SSH, the Jasper socket, or WinRM); BootstrapMethodNone and
+	// BootstrapMethodUserData hosts return before this point and mount their
+	// own volumes, if any, outside of app server control.
+	if err = j.mountVolumes(ctx, targetHost, settings); err != nil {
+		return errors.Wrapf(err, "error mounting volumes on host %s", targetHost.Id)
 	}
 
 	// Do not copy setup scripts to task-spawned hosts
@@ -296,15 +348,16 @@ func (j *setupHostJob) runHostSetup(ctx context.Context, targetHost *host.Host,
 		if targetHost.Distro.IsPowerShellSetup() {
 			scriptName = evergreen.PowerShellSetupScriptName
 		}
-		err = j.copyScript(ctx, settings, targetHost, filepath.Join("~", scriptName), targetHost.Distro.Setup)
+		err = j.copyScript(ctx, settings, targetHost, filepath.Join(targetHost.Distro.HomeDir(), scriptName), targetHost.Distro.Setup)
 		if err != nil {
 			return errors.Wrapf(err, "error copying setup script %s to host %s",
 				scriptName, targetHost.Id)
 		}
+		event.LogHostProvisionEvent(targetHost.Id, event.ScriptCopied, message.Fields{"name": scriptName})
 	}
 
 	if targetHost.Distro.Teardown != "" {
-		err = j.copyScript(ctx, settings, targetHost, filepath.Join("~", evergreen.TeardownScriptName), targetHost.Distro.Teardown)
+		err = j.copyScript(ctx, settings, targetHost, filepath.Join(targetHost.Distro.HomeDir(), evergreen.TeardownScriptName), targetHost.Distro.Teardown)
 		if err != nil {
 			return errors.Wrapf(err, "error copying teardown script %s to host %s",
 				evergreen.TeardownScriptName, targetHost.Id)
@@ -318,6 +371,10 @@ func (j *setupHostJob) runHostSetup(ctx context.Context, targetHost *host.Host,
 // on the host, downloading the latest version of Jasper, and restarting the
 // Jasper service.
 func setupJasper(ctx context.Context, env evergreen.Environment, settings *evergreen.Settings, h *host.Host) error {
+	if h.Distro.BootstrapSettings.Method == distro.BootstrapMethodSocket {
+		return setupJasperOverSocket(ctx, env, settings, h)
+	}
+
 	sshOptions, err := h.GetSSHOptions(settings)
 	if err != nil {
 		return errors.Wrapf(err, "error getting ssh options for host %s", h.Id)
@@ -338,6 +395,125 @@ func setupJasper(ctx context.Context, env evergreen.Environment, settings *everg
 	return nil
 }
 
+// setupJasperOverSocket performs the same steps as setupJasper, but delivers
+// credentials and commands over the host's Unix domain socket transport
+// rather than SSH. This is used for hosts that are co-located with the app
+// server (e.g. single-tenant containers) or that are reachable via an SSH
+// ControlMaster multiplexed socket, where spinning up a full SSH session for
+// every provisioning step adds unnecessary latency.
+func setupJasperOverSocket(ctx context.Context, env evergreen.Environment, settings *evergreen.Settings, h *host.Host) error {
+	if h.Distro.BootstrapSettings.SocketPath == "" {
+		return errors.Errorf("host %s declared socket bootstrap method but has no socket path", h.Id)
+	}
+
+	if err := putJasperCredentialsOverSocket(ctx, env, settings, h); err != nil {
+		return errors.Wrap(err, "error putting Jasper credentials on remote host over socket")
+	}
+
+	if err := doFetchAndReinstallJasperOverSocket(ctx, env, h); err != nil {
+		return errors.Wrap(err, "error starting Jasper service on remote host over socket")
+	}
+
+	return nil
+}
+
+// putJasperCredentialsOverSocket creates Jasper credentials for the host and
+// writes the credentials file to the host using the Unix domain socket
+// transport instead of SSH.
+func putJasperCredentialsOverSocket(ctx context.Context, env evergreen.Environment, settings *evergreen.Settings, h *host.Host) error {
+	creds, err := h.GenerateJasperCredentials(ctx, env)
+	if err != nil {
+		return errors.Wrap(err, "could not generate Jasper credentials for host")
+	}
+
+	writeCmds, err := h.WriteJasperCredentialsFilesCommands(settings.Splunk, creds)
+	if err != nil {
+		return errors.Wrap(err, "could not get command to write Jasper credentials file")
+	}
+
+	grip.Info(message.Fields{
+		"message": "putting Jasper credentials on host over socket",
+		"host":    h.Id,
+		"distro":  h.Distro.Id,
+		"socket":  h.Distro.BootstrapSettings.SocketPath,
+	})
+
+	ctx, cancel := context.WithTimeout(ctx, socketTimeout)
+	defer cancel()
+
+	if logs, err := h.RunSocketCommandLiterally(ctx, writeCmds); err != nil {
+		return errors.Wrapf(err, "error copying credentials to remote machine over socket: command returned %s", logs)
+	}
+
+	if err := h.SaveJasperCredentials(ctx, env, creds); err != nil {
+		return errors.Wrap(err, "error saving credentials")
+	}
+
+	return nil
+}
+
+// doFetchAndReinstallJasperOverSocket runs the command that downloads the
+// latest Jasper binary and restarts the service over the socket transport.
+func doFetchAndReinstallJasperOverSocket(ctx context.Context, env evergreen.Environment, h *host.Host) error {
+	cmd := h.FetchAndReinstallJasperCommands(env.Settings())
+	if logs, err := h.RunSocketCommandLiterally(ctx, cmd); err != nil {
+		return errors.Wrapf(err, "error while fetching Jasper binary and installing service on remote host over socket: command returned '%s'", logs)
+	}
+	return nil
+}
+
+// setupJasperOverWinRM sets up the Jasper service on a Windows host by
+// putting the credentials on the host and installing the service via WinRM,
+// rather than SSHing in to run a powershell script. Unlike setupJasper, it
+// does not run setupServiceUser, which still reaches the host over SSH.
+func setupJasperOverWinRM(ctx context.Context, env evergreen.Environment, settings *evergreen.Settings, h *host.Host) error {
+	winrmOptions, err := h.GetWinRMOptions(settings)
+	if err != nil {
+		return errors.Wrapf(err, "error getting WinRM options for host %s", h.Id)
+	}
+
+	if err := putJasperCredentialsOverWinRM(ctx, env, settings, h, winrmOptions); err != nil {
+		return errors.Wrap(err, "error putting Jasper credentials on remote host over WinRM")
+	}
+
+	cmd := h.FetchAndReinstallJasperCommands(env.Settings())
+	if logs, err := h.RunWinRMCommand(ctx, cmd, winrmOptions); err != nil {
+		return errors.Wrapf(err, "error while fetching Jasper binary and installing service over WinRM: command returned '%s'", logs)
+	}
+
+	return nil
+}
+
+// putJasperCredentialsOverWinRM creates Jasper credentials for the host and
+// copies the credentials file to the host via WinRM.
+func putJasperCredentialsOverWinRM(ctx context.Context, env evergreen.Environment, settings *evergreen.Settings, h *host.Host, winrmOptions host.WinRMOptions) error {
+	creds, err := h.GenerateJasperCredentials(ctx, env)
+	if err != nil {
+		return errors.Wrap(err, "could not generate Jasper credentials for host")
+	}
+
+	writeCmds, err := h.WriteJasperCredentialsFilesCommands(settings.Splunk, creds)
+	if err != nil {
+		return errors.Wrap(err, "could not get command to write Jasper credentials file")
+	}
+
+	grip.Info(message.Fields{
+		"message": "putting Jasper credentials on host over WinRM",
+		"host":    h.Id,
+		"distro":  h.Distro.Id,
+	})
+
+	if logs, err := h.RunWinRMCommand(ctx, writeCmds, winrmOptions); err != nil {
+		return errors.Wrapf(err, "error copying credentials to remote machine over WinRM: command returned %s", logs)
+	}
+
+	if err := h.SaveJasperCredentials(ctx, env, creds); err != nil {
+		return errors.Wrap(err, "error saving credentials")
+	}
+
+	return nil
+}
+
 // putJasperCredentials creates Jasper credentials for the host and puts the
 // credentials file on the host.
 func putJasperCredentials(ctx context.Context, env evergreen.Environment, settings *evergreen.Settings, h *host.Host, sshOptions []string) error {
@@ -482,10 +658,54 @@ func copyScript(ctx context.Context, env evergreen.Environment, settings *evergr
 	return errors.Wrap(err, "error copying script to remote machine")
 }
 
-// copyScript writes a given script as file "name" to the target host. This works
-// by creating a local copy of the script on the runner's machine, scping it over
-// then removing the local copy.
+// copyScript writes a given script as file "name" to the target host, preferring
+// to stream it directly over the existing Jasper RPC connection and falling
+// back to scp if that write fails.
 func (j *setupHostJob) copyScript(ctx context.Context, settings *evergreen.Settings, target *host.Host, name, script string) error {
+	if target.Distro.BootstrapSettings.Method == distro.BootstrapMethodSocket {
+		return j.copyScriptOverSocket(ctx, settings, target, name, script)
+	}
+	if target.Distro.BootstrapSettings.Method == distro.BootstrapMethodWinRM {
+		return j.copyScriptOverWinRM(ctx, settings, target, name, script)
+	}
+
+	expanded, err := expandScript(script, settings)
+	if err != nil {
+		return errors.Wrapf(err, "error expanding script for host %s", target.Id)
+	}
+
+	// Jasper is already running by the time this is called, so stream the
+	// script directly over the existing Jasper connection rather than
+	// round-tripping through a local temp file and an scp subprocess.
+	startAt := time.Now()
+	if err := target.WriteRemoteFile(ctx, j.env, name, 0700, []byte(expanded)); err == nil {
+		grip.Debug(message.Fields{
+			"job":           j.ID(),
+			"operation":     "copy script via Jasper RPC",
+			"distro":        target.Distro.Id,
+			"host":          target.Host,
+			"name":          name,
+			"duration_secs": time.Since(startAt).Seconds(),
+		})
+		return nil
+	} else {
+		grip.Notice(message.WrapError(err, message.Fields{
+			"message": "failed to write script over Jasper RPC, falling back to scp",
+			"job":     j.ID(),
+			"distro":  target.Distro.Id,
+			"host":    target.Host,
+			"name":    name,
+		}))
+	}
+
+	return j.copyScriptViaSCP(ctx, settings, target, name, expanded)
+}
+
+// copyScriptViaSCP writes a given script as file "name" to the target host by
+// creating a local copy of the script on the runner's machine, scping it
+// over, then removing the local copy. This is only used as a fallback for
+// when copyScript fails to write the script over Jasper RPC.
+func (j *setupHostJob) copyScriptViaSCP(ctx context.Context, settings *evergreen.Settings, target *host.Host, name, expanded string) error {
 	// parse the hostname into the user, host and port
 	startAt := time.Now()
 
@@ -529,10 +749,6 @@ func (j *setupHostJob) copyScript(ctx context.Context, settings *evergreen.Setti
 		})
 	}()
 
-	expanded, err := expandScript(script, settings)
-	if err != nil {
-		return errors.Wrapf(err, "error expanding script for host %s", target.Id)
-	}
 	if _, err = io.WriteString(file, expanded); err != nil {
 		return errors.Wrap(err, "error writing local script")
 	}
@@ -574,6 +790,68 @@ func buildScpCommand(src, dst string, info *util.StaticHostInfo, user string, op
 	return append(append([]string{"scp", "-vvv", "-P", info.Port}, opts...), src, fmt.Sprintf("%s@%s:%s", user, info.Hostname, dst))
 }
 
+// copyScriptOverSocket writes the expanded script directly to the target
+// host's Unix domain socket transport, skipping the temp file and scp
+// subprocess entirely.
+func (j *setupHostJob) copyScriptOverSocket(ctx context.Context, settings *evergreen.Settings, target *host.Host, name, script string) error {
+	startAt := time.Now()
+
+	expanded, err := expandScript(script, settings)
+	if err != nil {
+		return errors.Wrapf(err, "error expanding script for host %s", target.Id)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, socketTimeout)
+	defer cancel()
+
+	if err := target.WriteSocketFile(ctx, name, 0700, []byte(expanded)); err != nil {
+		return errors.Wrapf(err, "error writing script %s to remote machine over socket", name)
+	}
+
+	grip.Debug(message.Fields{
+		"job":           j.ID(),
+		"operation":     "copy script over socket",
+		"distro":        target.Distro.Id,
+		"host":          target.Host,
+		"name":          name,
+		"duration_secs": time.Since(startAt).Seconds(),
+	})
+
+	return nil
+}
+
+// copyScriptOverWinRM writes the expanded script to the target host's
+// WinRM file transfer, avoiding the SSH+scp assumptions baked into
+// buildScpCommand that don't apply to Windows hosts.
+func (j *setupHostJob) copyScriptOverWinRM(ctx context.Context, settings *evergreen.Settings, target *host.Host, name, script string) error {
+	startAt := time.Now()
+
+	expanded, err := expandScript(script, settings)
+	if err != nil {
+		return errors.Wrapf(err, "error expanding script for host %s", target.Id)
+	}
+
+	winrmOptions, err := target.GetWinRMOptions(settings)
+	if err != nil {
+		return errors.Wrapf(err, "error getting WinRM options for host %s", target.Id)
+	}
+
+	if err := target.CopyFileWinRM(ctx, name, []byte(expanded), winrmOptions); err != nil {
+		return errors.Wrapf(err, "error copying script to remote machine over WinRM")
+	}
+
+	grip.Debug(message.Fields{
+		"job":           j.ID(),
+		"operation":     "copy script over WinRM",
+		"distro":        target.Distro.Id,
+		"host":          target.Host,
+		"name":          name,
+		"duration_secs": time.Since(startAt).Seconds(),
+	})
+
+	return nil
+}
+
 // Build the setup script that will need to be run on the specified host.
 func expandScript(s string, settings *evergreen.Settings) (string, error) {
 	// replace expansions in the script
@@ -603,9 +881,24 @@ func (j *setupHostJob) provisionHost(ctx context.Context, h *host.Host, settings
 		"operation":     "increment provisioning errors failed",
 	}))
 
+	if err := j.provisionVolumes(ctx, h, settings); err != nil {
+		if shouldRetryProvisioning(settings, h) {
+			return nil
+		}
+
+		event.LogProvisionFailed(h.Id, "")
+		grip.Error(message.WrapError(h.SetUnprovisioned(), message.Fields{
+			"operation": "setting host unprovisioned",
+			"distro":    h.Distro.Id,
+			"job":       j.ID(),
+			"host":      h.Id,
+		}))
+		return errors.Wrapf(err, "error provisioning volumes for host %s", h.Id)
+	}
+
 	err := j.runHostSetup(ctx, h, settings)
 	if err != nil {
-		if shouldRetryProvisioning(h) {
+		if shouldRetryProvisioning(settings, h) {
 			return nil
 		}
 
@@ -628,7 +921,7 @@ func (j *setupHostJob) provisionHost(ctx context.Context, h *host.Host, settings
 	// If this is a spawn host
 	if h.ProvisionOptions != nil && h.ProvisionOptions.LoadCLI {
 		grip.Infof("Uploading client binary to host %s", h.Id)
-		lcr, err := j.loadClient(ctx, h, settings)
+		lcrs, err := j.loadClient(ctx, h, settings)
 		if err != nil {
 			grip.Error(message.WrapError(err, message.Fields{
 				"message": "failed to load client binary onto host",
@@ -645,6 +938,7 @@ func (j *setupHostJob) provisionHost(ctx context.Context, h *host.Host, settings
 			}))
 			return errors.Wrapf(err, "Failed to load client binary onto host %s: %+v", h.Id, err)
 		}
+		event.LogHostProvisionEvent(h.Id, event.ClientLoaded, nil)
 
 		sshOptions, err := h.GetSSHOptions(settings)
 		if err != nil {
@@ -683,21 +977,25 @@ func (j *setupHostJob) provisionHost(ctx context.Context, h *host.Host, settings
 		}
 
 		if h.ProvisionOptions.OwnerId != "" && len(h.ProvisionOptions.TaskId) > 0 {
-			grip.Info(message.Fields{
-				"message": "fetching data for task on host",
-				"task":    h.ProvisionOptions.TaskId,
-				"distro":  h.Distro.Id,
-				"host":    h.Id,
-				"job":     j.ID(),
-			})
-
-			grip.Error(message.WrapError(j.fetchRemoteTaskData(ctx, h.ProvisionOptions.TaskId, lcr.BinaryPath, lcr.ConfigPath, h, settings),
-				message.Fields{
-					"message": "failed to fetch data onto host",
+			for _, lcr := range lcrs {
+				grip.Info(message.Fields{
+					"message": "fetching data for task on host",
 					"task":    h.ProvisionOptions.TaskId,
+					"distro":  h.Distro.Id,
 					"host":    h.Id,
+					"agent":   lcr.AgentName,
 					"job":     j.ID(),
-				}))
+				})
+
+				grip.Error(message.WrapError(j.fetchRemoteTaskData(ctx, h.ProvisionOptions.TaskId, lcr.AgentName, lcr.BinaryPath, lcr.ConfigPath, h, settings),
+					message.Fields{
+						"message": "failed to fetch data onto host",
+						"task":    h.ProvisionOptions.TaskId,
+						"host":    h.Id,
+						"agent":   lcr.AgentName,
+						"job":     j.ID(),
+					}))
+			}
 		}
 	}
 
@@ -726,18 +1024,106 @@ func (j *setupHostJob) provisionHost(ctx context.Context, h *host.Host, settings
 	return nil
 }
 
-// loadClientResult indicates the locations on a target host where the CLI binary and it's config
-// file have been written to.
+// provisionVolumes allocates and attaches the ephemeral volumes listed in the
+// host's distro before the rest of setup runs. Task-spawned hosts get the
+// same distro volumes; no volume spec is taken from the task, so a
+// task-spawned host whose distro lists no volumes gets none. Attached volumes
+// are recorded on the host document so that they get torn down along with
+// the host.
+func (j *setupHostJob) provisionVolumes(ctx context.Context, h *host.Host, settings *evergreen.Settings) error {
+	specs := h.Distro.Volumes
+	if !h.SpawnOptions.SpawnedByTask && len(specs) == 0 {
+		return nil
+	}
+
+	mgrOpts, err := cloud.GetManagerOptions(h.Distro)
+	if err != nil {
+		return errors.Wrapf(err, "can't get ManagerOpts for '%s'", h.Id)
+	}
+	cloudMgr, err := cloud.GetManager(ctx, j.env, mgrOpts)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get cloud manager for host %s with provider %s", h.Id, h.Provider)
+	}
+
+	for _, spec := range specs {
+		grip.Info(message.Fields{
+			"message": "provisioning volume for host",
+			"host":    h.Id,
+			"distro":  h.Distro.Id,
+			"job":     j.ID(),
+			"spec":    spec,
+		})
+
+		vol, err := cloudMgr.ProvisionVolume(ctx, h, spec)
+		if err != nil {
+			return errors.Wrapf(err, "error provisioning volume for host %s", h.Id)
+		}
+
+		if err := h.AddVolume(*vol); err != nil {
+			return errors.Wrapf(err, "error recording volume %s on host %s", vol.ID, h.Id)
+		}
+	}
+
+	return nil
+}
+
+// mountVolumes mounts any volumes that were attached by provisionVolumes and
+// chowns them to the distro user so tasks running on the host can use them
+// as scratch or cache space. It runs POSIX shell commands through the host's
+// communicator (SSH unless the host's communication method is WinRM);
+// Windows hosts are skipped and get no volumes mounted.
+func (j *setupHostJob) mountVolumes(ctx context.Context, targetHost *host.Host, settings *evergreen.Settings) error {
+	if len(targetHost.Volumes) == 0 {
+		return nil
+	}
+
+	if targetHost.Distro.IsWindows() {
+		grip.Warning(message.Fields{
+			"message": "mounting volumes on Windows hosts is not yet supported, skipping",
+			"host":    targetHost.Id,
+			"distro":  targetHost.Distro.Id,
+			"job":     j.ID(),
+		})
+		return nil
+	}
+
+	comm, err := communicator.New(j.env, targetHost, settings)
+	if err != nil {
+		return errors.Wrapf(err, "error getting communicator for host %s", targetHost.Id)
+	}
+
+	for _, vol := range targetHost.Volumes {
+		// The cloud provider can report a volume as attached before its
+		// device node actually shows up on the host, so wait for it rather
+		// than racing the kernel with the mount command.
+		cmd := fmt.Sprintf("sudo mkdir -p %s && for i in $(seq 1 60); do [ -e %s ] && break; sleep 1; done && sudo mount %s %s && sudo chown -R %s %s",
+			vol.MountPoint, vol.DeviceName, vol.DeviceName, vol.MountPoint, targetHost.Distro.User, vol.MountPoint)
+		if _, err := comm.Run(ctx, cmd); err != nil {
+			return errors.Wrapf(err, "error mounting volume %s on host %s", vol.ID, targetHost.Id)
+		}
+	}
+
+	return nil
+}
+
+// loadClientResult indicates the locations on a target host where one
+// agent's CLI binary and config file have been written to.
 type loadClientResult struct {
+	AgentName  string
 	BinaryPath string
 	ConfigPath string
 }
 
-// loadClient places the evergreen command line client on the host, places a copy of the user's
-// settings onto the host, and makes the binary appear in the $PATH when the user logs in.
-// If successful, returns an instance of loadClientResult which contains the paths where the
-// binary and config file were written to.
-func (j *setupHostJob) loadClient(ctx context.Context, target *host.Host, settings *evergreen.Settings) (*loadClientResult, error) {
+// defaultAgentSlot is used when a host declares no named agent slots, so that
+// single-agent hosts behave exactly as before.
+var defaultAgentSlot = host.AgentSlot{Name: "", OwnerId: ""}
+
+// loadClient places the evergreen command line client on the host once per
+// named agent slot declared on the host (or a single default slot for
+// ordinary spawn hosts), each with its own API key/user identity under
+// ~/<agentName>/. This lets a host run several distinct agents, e.g. a
+// build-host agent plus an in-container test-runner agent.
+func (j *setupHostJob) loadClient(ctx context.Context, target *host.Host, settings *evergreen.Settings) ([]loadClientResult, error) {
 	if target.ProvisionOptions == nil {
 		return nil, errors.New("ProvisionOptions is nil")
 	}
@@ -745,53 +1131,74 @@ func (j *setupHostJob) loadClient(ctx context.Context, target *host.Host, settin
 		return nil, errors.New("OwnerId not set")
 	}
 
-	// get the information about the owner of the host
-	owner, err := user.FindOne(user.ById(target.ProvisionOptions.OwnerId))
+	comm, err := communicator.New(j.env, target, settings)
 	if err != nil {
-		return nil, errors.Wrapf(err, "couldn't fetch owner %v for host", target.ProvisionOptions.OwnerId)
+		return nil, errors.Wrapf(err, "error getting communicator for host %s", target.Id)
 	}
 
-	// 1. mkdir the destination directory on the host,
-	//    and modify ~/.profile so the target binary will be on the $PATH
-	targetDir := "cli_bin"
-	hostSSHInfo, err := target.GetSSHInfo()
-	if err != nil {
-		return nil, errors.Wrapf(err, "error parsing ssh info %s", target.Host)
+	slots := target.ProvisionOptions.Agents
+	if len(slots) == 0 {
+		slot := defaultAgentSlot
+		slot.OwnerId = target.ProvisionOptions.OwnerId
+		slots = []host.AgentSlot{slot}
 	}
 
-	sshOptions, err := target.GetSSHOptions(settings)
+	results := make([]loadClientResult, 0, len(slots))
+	for _, slot := range slots {
+		lcr, err := j.loadClientForAgent(ctx, target, settings, comm, slot)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error loading client for agent '%s' on host %s", slot.Name, target.Id)
+		}
+		results = append(results, *lcr)
+	}
+
+	return results, nil
+}
+
+// loadClientForAgent places the evergreen command line client on the host
+// for a single named agent slot, places a copy of that agent's settings onto
+// the host, and makes the binary appear in the $PATH when the user logs in.
+// If successful, returns the paths where the binary and config file were
+// written to.
+func (j *setupHostJob) loadClientForAgent(ctx context.Context, target *host.Host, settings *evergreen.Settings, comm communicator.Communicator, slot host.AgentSlot) (*loadClientResult, error) {
+	ownerId := slot.OwnerId
+	if ownerId == "" {
+		ownerId = target.ProvisionOptions.OwnerId
+	}
+
+	// get the information about the owner of the agent
+	owner, err := user.FindOne(user.ById(ownerId))
 	if err != nil {
-		return nil, errors.Wrapf(err, "Error getting ssh options for host %v", target.Id)
+		return nil, errors.Wrapf(err, "couldn't fetch owner %v for host", ownerId)
+	}
+
+	// 1. mkdir the destination directory on the host,
+	//    and modify ~/.profile so the target binary will be on the $PATH
+	targetDir := "cli_bin"
+	if slot.Name != "" {
+		targetDir = slot.Name
 	}
-	sshOptions = append(sshOptions, "-o", "UserKnownHostsFile=/dev/null")
 
 	mkdirctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
-	output, err := target.RunSSHCommandLiterally(mkdirctx, fmt.Sprintf("mkdir -m 777 -p ~/%s && (echo 'export PATH=\"$PATH:~/%s\"' >> ~/.profile || true; echo 'export PATH=\"$PATH:~/%s\"' >> ~/.bash_profile || true)", targetDir, targetDir, targetDir), sshOptions)
-	if err != nil {
-		return nil, errors.Wrapf(err, "error running setup command for cli: %s", output)
+	if err := comm.MakeDir(mkdirctx, "~/"+targetDir, 0777); err != nil {
+		return nil, errors.Wrapf(err, "error creating cli directory on host %s", target.Id)
+	}
+	if err := comm.AppendToShellProfile(mkdirctx, fmt.Sprintf(`export PATH="$PATH:~/%s"`, targetDir)); err != nil {
+		return nil, errors.Wrapf(err, "error updating shell profile on host %s", target.Id)
 	}
 
 	// run the command to curl the agent
 	curlctx, cancel := context.WithTimeout(ctx, 2*time.Minute)
 	defer cancel()
 
-	curlOut := &util.CappedWriter{
-		Buffer:   &bytes.Buffer{},
-		MaxBytes: 1024 * 1024,
-	}
-
-	curlcmd := j.env.JasperManager().CreateCommand(curlctx).Host(hostSSHInfo.Hostname).User(target.User).
-		ExtendRemoteArgs("-p", hostSSHInfo.Port).ExtendRemoteArgs(sshOptions...).
-		RedirectErrorToOutput(true).SetOutputWriter(curlOut).
-		Append(target.CurlCommand(settings))
-
-	if err = curlcmd.Run(curlctx); err != nil {
-		return nil, errors.Wrapf(err, "error running curl command for cli, %s", curlOut.Buffer.String())
+	curlSink := newStreamingLogSink(fmt.Sprintf("curl-cli-%s", target.Id), 0)
+	if err := comm.RunStreaming(curlctx, target.CurlCommand(settings), curlSink); err != nil {
+		return nil, errors.Wrapf(err, "error running curl command for cli, %s", curlSink.Summary())
 	}
 
-	// 2. Write a settings file for the user that owns the host, and scp it to the directory
+	// 2. Write a settings file for the user that owns this agent slot
 	outputStruct := struct {
 		APIKey        string `json:"api_key"`
 		APIServerHost string `json:"api_server_host"`
@@ -808,93 +1215,170 @@ func (j *setupHostJob) loadClient(ctx context.Context, target *host.Host, settin
 		return nil, errors.WithStack(err)
 	}
 
-	tempFileName, err := util.WriteTempFile("", outputJSON)
-	if err != nil {
-		return nil, errors.WithStack(err)
-	}
-	defer os.Remove(tempFileName)
-
-	scpOut := &util.CappedWriter{
-		Buffer:   &bytes.Buffer{},
-		MaxBytes: 1024 * 1024,
-	}
-
-	scpArgs := buildScpCommand(tempFileName, filepath.Join("~", targetDir, ".evergreen.yml"), hostSSHInfo, target.User, sshOptions)
-	scpYmlCommand := j.env.JasperManager().CreateCommand(ctx).Add(scpArgs).
-		RedirectErrorToOutput(true).SetOutputWriter(scpOut)
-
-	ctx, cancel = context.WithTimeout(ctx, 30*time.Second)
+	ymlctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
-	if err = scpYmlCommand.Run(ctx); err != nil {
-		return nil, errors.Wrapf(err, "error running SCP command for evergreen.yml, %v", scpOut.String())
+	configPath := filepath.Join(target.Distro.HomeDir(), targetDir, ".evergreen.yml")
+	if err := comm.CopyFile(ymlctx, configPath, 0600, outputJSON); err != nil {
+		return nil, errors.Wrapf(err, "error writing evergreen.yml to host %s", target.Id)
 	}
 
 	return &loadClientResult{
-		BinaryPath: filepath.Join("~", "evergreen"),
+		AgentName:  slot.Name,
+		BinaryPath: filepath.Join(target.Distro.HomeDir(), "evergreen"),
 		ConfigPath: fmt.Sprintf("%s/.evergreen.yml", targetDir),
 	}, nil
 }
 
-func (j *setupHostJob) fetchRemoteTaskData(ctx context.Context, taskId, cliPath, confPath string, target *host.Host, settings *evergreen.Settings) error {
-	hostSSHInfo, err := target.GetSSHInfo()
+// fetchRemoteTaskData fetches task source and artifacts onto the host for
+// the given agent slot, so task dispatch can address a specific agent on a
+// multi-agent host as "<host>.<agent>".
+func (j *setupHostJob) fetchRemoteTaskData(ctx context.Context, taskId, agentName, cliPath, confPath string, target *host.Host, settings *evergreen.Settings) error {
+	comm, err := communicator.New(j.env, target, settings)
 	if err != nil {
-		return errors.Wrapf(err, "error parsing ssh info %s", target.Host)
+		return errors.Wrapf(err, "error getting communicator for host %s", target.Id)
 	}
 
-	sshOptions, err := target.GetSSHOptions(settings)
-	if err != nil {
-		return errors.Wrapf(err, "Error getting ssh options for host %v", target.Id)
-	}
-	sshOptions = append(sshOptions, "-o", "UserKnownHostsFile=/dev/null")
-
-	cmdOutput := &util.CappedWriter{
-		Buffer:   &bytes.Buffer{},
-		MaxBytes: 1024 * 1024,
-	}
 	fetchCmd := fmt.Sprintf("%s -c %s fetch -t %s --source --artifacts --dir='%s'", cliPath, confPath, taskId, target.Distro.WorkDir)
 
-	makeShellCmd := j.env.JasperManager().CreateCommand(ctx).Host(hostSSHInfo.Hostname).User(target.User).
-		ExtendRemoteArgs("-p", hostSSHInfo.Port).ExtendRemoteArgs(sshOptions...).
-		RedirectErrorToOutput(true).SetOutputWriter(cmdOutput).
-		Append(fetchCmd)
-
-	// run the make shell command with a timeout
+	// run the fetch command with a timeout
 	var cancel context.CancelFunc
 	ctx, cancel = context.WithTimeout(ctx, 15*time.Minute)
 	defer cancel()
 
-	err = makeShellCmd.Run(ctx)
+	sink := newStreamingLogSink(fmt.Sprintf("fetch-artifacts-%s", taskId), 0)
+	err = comm.RunStreaming(ctx, fetchCmd, sink)
 
 	grip.Error(message.WrapError(err, message.Fields{
 		"message": fmt.Sprintf("fetch-artifacts-%s", taskId),
-		"host":    hostSSHInfo.Hostname,
+		"agent":   agentName,
+		"host":    target.Host,
 		"cmd":     fetchCmd,
 		"job":     j.ID(),
-		"output":  cmdOutput.Buffer.String(),
+		"output":  sink.Summary(),
 	}))
 
 	return errors.WithStack(err)
 }
 
+// distroPlacementFailureThreshold is the number of consecutive provisioning
+// failures on a host before tryRequeue asks the scheduler to re-place the
+// next attempt on a different candidate host/distro rather than retrying the
+// same one.
+const distroPlacementFailureThreshold = 5
+
 func (j *setupHostJob) tryRequeue(ctx context.Context) {
-	if shouldRetryProvisioning(j.host) && j.env.RemoteQueue().Started() {
-		job := NewHostSetupJob(j.env, *j.host, fmt.Sprintf("attempt-%d", j.host.ProvisionAttempts))
-		job.UpdateTimeInfo(amboy.JobTimeInfo{
-			WaitUntil: time.Now().Add(time.Minute),
-		})
-		err := j.env.RemoteQueue().Put(ctx, job)
-		grip.Critical(message.WrapError(err, message.Fields{
-			"message":  "failed to requeue setup job",
-			"host":     j.host.Id,
-			"job":      j.ID(),
-			"distro":   j.host.Distro.Id,
-			"attempts": j.host.ProvisionAttempts,
-		}))
-		j.AddError(err)
+	settings := j.env.Settings()
+	if !shouldRetryProvisioning(settings, j.host) || !j.env.RemoteQueue().Started() {
+		return
 	}
+
+	nextHost := j.host
+	if j.host.ProvisionAttempts >= distroPlacementFailureThreshold {
+		if replacement, err := j.rePlaceHost(ctx); err != nil {
+			grip.Error(message.WrapError(err, message.Fields{
+				"message":  "failed to re-place host after repeated provisioning failures, retrying on same host",
+				"host":     j.host.Id,
+				"job":      j.ID(),
+				"distro":   j.host.Distro.Id,
+				"attempts": j.host.ProvisionAttempts,
+			}))
+		} else if replacement != nil {
+			grip.Error(message.WrapError(j.host.SetDecommissioned(evergreen.User, "replaced after repeated provisioning failures"), message.Fields{
+				"message":     "failed to decommission host after re-placing it",
+				"host":        j.host.Id,
+				"job":         j.ID(),
+				"distro":      j.host.Distro.Id,
+				"attempts":    j.host.ProvisionAttempts,
+				"replacement": replacement.Id,
+			}))
+			nextHost = replacement
+		}
+	}
+
+	waitUntil := time.Now().Add(provisionRetryBackoff(settings, nextHost.ProvisionAttempts))
+
+	job := NewHostSetupJob(j.env, *nextHost, fmt.Sprintf("attempt-%d", nextHost.ProvisionAttempts))
+	job.UpdateTimeInfo(amboy.JobTimeInfo{
+		WaitUntil: waitUntil,
+	})
+	err := j.env.RemoteQueue().Put(ctx, job)
+	grip.Critical(message.WrapError(err, message.Fields{
+		"message":    "failed to requeue setup job",
+		"host":       nextHost.Id,
+		"job":        j.ID(),
+		"distro":     nextHost.Distro.Id,
+		"attempts":   nextHost.ProvisionAttempts,
+		"wait_until": waitUntil,
+	}))
+	j.AddError(err)
+}
+
+// defaultProvisionRetryBackoffBase and defaultProvisionRetryBackoffCeiling
+// are used when evergreen.Settings.HostInit doesn't configure its own
+// backoff parameters.
+const (
+	defaultProvisionRetryBackoffBase    = time.Minute
+	defaultProvisionRetryBackoffCeiling = 10 * time.Minute
+)
+
+// provisionRetryBackoff returns the delay before the next provisioning
+// attempt for a host that has failed attempts times already: exponential
+// backoff keyed on attempts, clamped to a configurable ceiling, with ±20%
+// jitter so a provider-wide outage doesn't produce a thundering herd of
+// synchronized retries.
+func provisionRetryBackoff(settings *evergreen.Settings, attempts int) time.Duration {
+	base := defaultProvisionRetryBackoffBase
+	if settings.HostInit.ProvisionRetryBackoffBase > 0 {
+		base = time.Duration(settings.HostInit.ProvisionRetryBackoffBase)
+	}
+	ceiling := defaultProvisionRetryBackoffCeiling
+	if settings.HostInit.ProvisionRetryBackoffCeiling > 0 {
+		ceiling = time.Duration(settings.HostInit.ProvisionRetryBackoffCeiling)
+	}
+
+	// Compare in float space before converting to a time.Duration: for large
+	// attempts, base*2^attempts overflows int64, and the result of
+	// converting an out-of-range float to time.Duration is implementation-
+	// defined rather than reliably landing <=0.
+	delayFloat := float64(base) * math.Pow(2, float64(attempts))
+	delay := ceiling
+	if delayFloat > 0 && delayFloat <= float64(ceiling) {
+		delay = time.Duration(delayFloat)
+	}
+
+	jitter := time.Duration(float64(delay) * ((rand.Float64() * 0.4) - 0.2))
+	return delay + jitter
+}
+
+// rePlaceHost asks the cluster scheduler to place the task that owns this
+// host onto a different candidate host after repeated provisioning failures,
+// using the candidate's tags and current load/failure-rate scoring rather
+// than blindly retrying on the same host. Returns a nil host if the host
+// isn't task-owned or no better candidate is available.
+func (j *setupHostJob) rePlaceHost(ctx context.Context) (*host.Host, error) {
+	if j.host.ProvisionOptions == nil || j.host.ProvisionOptions.TaskId == "" {
+		return nil, nil
+	}
+
+	requirements := cloud.TaskRequirements{
+		TaskId:         j.host.ProvisionOptions.TaskId,
+		RequiredTags:   j.host.Distro.Tags,
+		ExcludeDistros: []string{j.host.Distro.Id},
+	}
+
+	replacement, err := cloud.GetScheduler(j.env).PlaceHost(ctx, requirements)
+	if err != nil {
+		return nil, errors.Wrap(err, "error placing replacement host")
+	}
+
+	return replacement, nil
 }
 
-func shouldRetryProvisioning(h *host.Host) bool {
-	return h.ProvisionAttempts <= provisionRetryLimit && h.Status == evergreen.HostProvisioning && !h.Provisioned
+func shouldRetryProvisioning(settings *evergreen.Settings, h *host.Host) bool {
+	limit := provisionRetryLimit
+	if settings.HostInit.ProvisionRetryLimit > 0 {
+		limit = settings.HostInit.ProvisionRetryLimit
+	}
+	return h.ProvisionAttempts <= limit && h.Status == evergreen.HostProvisioning && !h.Provisioned
 }
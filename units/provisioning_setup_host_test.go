@@ -0,0 +1,37 @@
+package units
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProvisionRetryBackoffUsesDefaultsWhenUnconfigured(t *testing.T) {
+	settings := &evergreen.Settings{}
+
+	delay := provisionRetryBackoff(settings, 0)
+	minDelay := time.Duration(float64(defaultProvisionRetryBackoffBase) * 0.8)
+	maxDelay := time.Duration(float64(defaultProvisionRetryBackoffBase) * 1.2)
+	assert.True(t, delay >= minDelay && delay <= maxDelay, "delay %s out of expected range [%s, %s]", delay, minDelay, maxDelay)
+}
+
+func TestProvisionRetryBackoffGrowsExponentially(t *testing.T) {
+	settings := &evergreen.Settings{}
+
+	first := provisionRetryBackoff(settings, 0)
+	second := provisionRetryBackoff(settings, 1)
+
+	// Jitter is only ±20%, so even in the worst case a doubled base delay
+	// should still exceed the previous attempt's delay.
+	assert.True(t, second > first, "expected attempt 1 (%s) to back off longer than attempt 0 (%s)", second, first)
+}
+
+func TestProvisionRetryBackoffClampsToCeiling(t *testing.T) {
+	settings := &evergreen.Settings{}
+
+	delay := provisionRetryBackoff(settings, 100)
+	maxDelay := time.Duration(float64(defaultProvisionRetryBackoffCeiling) * 1.2)
+	assert.True(t, delay <= maxDelay, "delay %s exceeded ceiling*1.2 (%s)", delay, maxDelay)
+}
@@ -0,0 +1,117 @@
+package units
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+)
+
+// defaultStreamingLogSinkBudget is the number of bytes kept at the head and
+// at the tail of a streamed command's output. Anything in between is
+// dropped, so an operator watching a long-running setup command sees live
+// progress without the final log message growing unbounded for multi-GB
+// artifact fetches.
+const defaultStreamingLogSinkBudget = 64 * 1024
+
+// streamingLogSink is an io.Writer that forwards complete lines to grip in
+// real time as they're written, while also keeping a rolling, size-bounded
+// copy of the output (first N bytes + last N bytes) for inclusion in the
+// final failure message.
+type streamingLogSink struct {
+	mu     sync.Mutex
+	budget int
+	prefix string
+
+	head       strings.Builder
+	headFull   bool
+	tail       []byte
+	totalBytes int64
+
+	lineBuf strings.Builder
+}
+
+// newStreamingLogSink returns a streamingLogSink that logs each non-empty line
+// it receives with prefix in the "message" field and the line in the "line"
+// field, and keeps up to budget bytes each of head and tail.
+func newStreamingLogSink(prefix string, budget int) *streamingLogSink {
+	if budget <= 0 {
+		budget = defaultStreamingLogSinkBudget
+	}
+	return &streamingLogSink{prefix: prefix, budget: budget}
+}
+
+func (s *streamingLogSink) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	n := len(p)
+	s.appendRolling(p)
+
+	s.lineBuf.Write(p)
+	for {
+		buffered := s.lineBuf.String()
+		idx := strings.IndexByte(buffered, '\n')
+		if idx < 0 {
+			break
+		}
+		line := strings.TrimRight(buffered[:idx], "\r")
+		if line != "" {
+			grip.Info(message.Fields{
+				"message": s.prefix,
+				"line":    line,
+			})
+		}
+		s.lineBuf.Reset()
+		s.lineBuf.WriteString(buffered[idx+1:])
+	}
+
+	return n, nil
+}
+
+// appendRolling keeps the first s.budget bytes ever written in head, and the
+// most recent s.budget bytes in tail, dropping everything in between.
+func (s *streamingLogSink) appendRolling(p []byte) {
+	s.totalBytes += int64(len(p))
+
+	if !s.headFull {
+		room := s.budget - s.head.Len()
+		if room > 0 {
+			if room > len(p) {
+				room = len(p)
+			}
+			s.head.Write(p[:room])
+		}
+		if s.head.Len() >= s.budget {
+			s.headFull = true
+		}
+	}
+
+	s.tail = append(s.tail, p...)
+	if len(s.tail) > s.budget {
+		s.tail = s.tail[len(s.tail)-s.budget:]
+	}
+}
+
+// Summary returns the head and tail of the output collected so far, noting
+// how many bytes in the middle were dropped. Once total output exceeds
+// 2*budget, head and tail no longer overlap and every byte in between truly
+// was dropped; below that, they cover some of the same bytes, so the
+// overlapping prefix is trimmed off the tail before it's appended.
+func (s *streamingLogSink) Summary() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	overlap := int64(s.head.Len()) + int64(len(s.tail)) - s.totalBytes
+	if overlap >= 0 {
+		if overlap > int64(len(s.tail)) {
+			overlap = int64(len(s.tail))
+		}
+		return s.head.String() + string(s.tail[overlap:])
+	}
+
+	dropped := -overlap
+	return s.head.String() + fmt.Sprintf("\n...[dropped %d bytes]...\n", dropped) + string(s.tail)
+}
@@ -0,0 +1,52 @@
+package units
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStreamingLogSinkSummaryWithinBudget(t *testing.T) {
+	sink := newStreamingLogSink("test", 10)
+	_, err := sink.Write([]byte("hello"))
+	assert.NoError(t, err)
+
+	// Total output never exceeded the budget, so head and tail cover the
+	// exact same bytes; Summary must not duplicate them.
+	assert.Equal(t, "hello", sink.Summary())
+}
+
+func TestStreamingLogSinkSummaryOverlappingHeadAndTail(t *testing.T) {
+	sink := newStreamingLogSink("test", 10)
+	// 15 bytes total: more than the 10-byte budget, but less than 2x, so
+	// head (first 10) and tail (last 10) overlap on bytes 5-9 (5 bytes).
+	_, err := sink.Write([]byte("0123456789abcde"))
+	assert.NoError(t, err)
+
+	// The overlap must be trimmed so the full, non-duplicated content comes
+	// back out.
+	assert.Equal(t, "0123456789abcde", sink.Summary())
+}
+
+func TestStreamingLogSinkSummaryDropsMiddle(t *testing.T) {
+	sink := newStreamingLogSink("test", 10)
+	content := strings.Repeat("a", 10) + strings.Repeat("x", 10) + strings.Repeat("b", 10)
+	_, err := sink.Write([]byte(content))
+	assert.NoError(t, err)
+
+	summary := sink.Summary()
+	assert.True(t, strings.HasPrefix(summary, strings.Repeat("a", 10)))
+	assert.True(t, strings.HasSuffix(summary, strings.Repeat("b", 10)))
+	assert.Contains(t, summary, "dropped 10 bytes")
+}
+
+func TestStreamingLogSinkSummaryAcrossMultipleWrites(t *testing.T) {
+	sink := newStreamingLogSink("test", 10)
+	for _, chunk := range []string{"01234", "56789", "abcde"} {
+		_, err := sink.Write([]byte(chunk))
+		assert.NoError(t, err)
+	}
+
+	assert.Equal(t, "0123456789abcde", sink.Summary())
+}